@@ -0,0 +1,13 @@
+package kafka
+
+// CheckpointStore persists the last processed offset per offsets-topic partition so that, on restart, Minion can
+// resume close to where it left off instead of always re-reading the whole (potentially many-GB) compacted offsets
+// topic from sarama.OffsetOldest. See Start, which consults it to pick a starting offset, and checkpointLoop, which
+// periodically persists progress to it.
+type CheckpointStore interface {
+	// Load returns the last checkpointed offset for partition. ok is false if no checkpoint has been recorded yet,
+	// in which case Start falls back to sarama.OffsetOldest.
+	Load(partition int32) (offset int64, ok bool, err error)
+	// Save persists offset as the last processed offset for partition.
+	Save(partition int32, offset int64) error
+}
@@ -0,0 +1,77 @@
+package kafka
+
+import (
+	"encoding/json"
+	"os"
+	"sync"
+)
+
+// FileCheckpointStore persists checkpoints for every partition as a single JSON file on local disk. It is the
+// simplest CheckpointStore and the right choice whenever Minion runs with a persistent local/attached volume.
+type FileCheckpointStore struct {
+	path string
+	mu   sync.Mutex
+}
+
+// NewFileCheckpointStore creates a CheckpointStore backed by the JSON file at path. The file is created on first
+// Save if it does not already exist.
+func NewFileCheckpointStore(path string) *FileCheckpointStore {
+	return &FileCheckpointStore{path: path}
+}
+
+// Load implements CheckpointStore
+func (s *FileCheckpointStore) Load(partition int32) (int64, bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.readAll()
+	if err != nil {
+		return 0, false, err
+	}
+	offset, ok := checkpoints[partition]
+	return offset, ok, nil
+}
+
+// Save implements CheckpointStore
+func (s *FileCheckpointStore) Save(partition int32, offset int64) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	checkpoints, err := s.readAll()
+	if err != nil {
+		return err
+	}
+	checkpoints[partition] = offset
+
+	encoded, err := json.Marshal(checkpoints)
+	if err != nil {
+		return err
+	}
+
+	// Write to a temp file and rename over the real path so a crash mid-write never leaves a truncated checkpoint
+	// file behind.
+	tmpPath := s.path + ".tmp"
+	if err := os.WriteFile(tmpPath, encoded, 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmpPath, s.path)
+}
+
+func (s *FileCheckpointStore) readAll() (map[int32]int64, error) {
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return make(map[int32]int64), nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make(map[int32]int64)
+	if len(data) == 0 {
+		return checkpoints, nil
+	}
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
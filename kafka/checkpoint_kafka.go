@@ -0,0 +1,97 @@
+package kafka
+
+import (
+	"strconv"
+	"time"
+
+	"github.com/Shopify/sarama"
+)
+
+// checkpointLoadIdleTimeout bounds how long Load waits for the next message once it has started consuming the
+// checkpoint topic. It exists because the topic's highest retained offset is not guaranteed to reach
+// highWaterMark-1: compaction can remove the tail (e.g. a tombstone past delete.retention.ms), so waiting for a
+// message at exactly that offset could block forever.
+const checkpointLoadIdleTimeout = 5 * time.Second
+
+// KafkaCheckpointStore persists checkpoints as key/value records on a dedicated, compacted Kafka topic, keyed by
+// partition id so that log compaction naturally retains only the latest checkpoint per partition. This is the
+// right choice when Minion instances have no shared/persistent disk to fall back to (e.g. a plain Kubernetes
+// Deployment).
+type KafkaCheckpointStore struct {
+	client   sarama.Client
+	producer sarama.SyncProducer
+	topic    string
+}
+
+// NewKafkaCheckpointStore creates a CheckpointStore backed by topic, which must already exist as a compacted,
+// single-partition topic.
+func NewKafkaCheckpointStore(client sarama.Client, producer sarama.SyncProducer, topic string) *KafkaCheckpointStore {
+	return &KafkaCheckpointStore{client: client, producer: producer, topic: topic}
+}
+
+// Save implements CheckpointStore
+func (s *KafkaCheckpointStore) Save(partition int32, offset int64) error {
+	_, _, err := s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: s.topic,
+		Key:   sarama.StringEncoder(strconv.Itoa(int(partition))),
+		Value: sarama.StringEncoder(strconv.FormatInt(offset, 10)),
+	})
+	return err
+}
+
+// Load implements CheckpointStore. Because the checkpoint topic is compacted and expected to stay tiny (one record
+// per offsets-topic partition), Load simply reads it from the start and keeps the last value seen for partition.
+func (s *KafkaCheckpointStore) Load(partition int32) (int64, bool, error) {
+	highWaterMark, err := s.client.GetOffset(s.topic, 0, sarama.OffsetNewest)
+	if err != nil {
+		return 0, false, err
+	}
+	if highWaterMark == 0 {
+		return 0, false, nil
+	}
+
+	consumer, err := sarama.NewConsumerFromClient(s.client)
+	if err != nil {
+		return 0, false, err
+	}
+	defer consumer.Close()
+
+	pconsumer, err := consumer.ConsumePartition(s.topic, 0, sarama.OffsetOldest)
+	if err != nil {
+		return 0, false, err
+	}
+	defer pconsumer.AsyncClose()
+
+	latest := make(map[int32]int64)
+	idleTimer := time.NewTimer(checkpointLoadIdleTimeout)
+	defer idleTimer.Stop()
+
+readLoop:
+	for {
+		select {
+		case msg, ok := <-pconsumer.Messages():
+			if !ok {
+				break readLoop
+			}
+			if key, err := strconv.Atoi(string(msg.Key)); err == nil {
+				if offset, err := strconv.ParseInt(string(msg.Value), 10, 64); err == nil {
+					latest[int32(key)] = offset
+				}
+			}
+			if msg.Offset >= highWaterMark-1 {
+				break readLoop
+			}
+			if !idleTimer.Stop() {
+				<-idleTimer.C
+			}
+			idleTimer.Reset(checkpointLoadIdleTimeout)
+		case <-idleTimer.C:
+			// Compaction can remove the tail of the topic, so the highest retained offset may never reach
+			// highWaterMark-1. Treat a quiet period as "caught up" instead of waiting for an offset that won't come.
+			break readLoop
+		}
+	}
+
+	offset, ok := latest[partition]
+	return offset, ok, nil
+}
@@ -0,0 +1,95 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+// S3CheckpointStore persists checkpoints for every partition as a single JSON object in an S3-compatible bucket
+// (AWS S3, MinIO, ...). It is the right choice for deployments where Minion instances don't share a local disk
+// (e.g. a Kubernetes Deployment without a persistent volume) but still want checkpoints to survive a restart.
+type S3CheckpointStore struct {
+	client *s3.S3
+	bucket string
+	key    string
+}
+
+// NewS3CheckpointStore creates a CheckpointStore which stores its checkpoint object at the given bucket/key using
+// sess. Point sess at an S3-compatible endpoint via its Config.Endpoint to target e.g. MinIO instead of AWS S3.
+func NewS3CheckpointStore(sess *session.Session, bucket, key string) *S3CheckpointStore {
+	return &S3CheckpointStore{client: s3.New(sess), bucket: bucket, key: key}
+}
+
+// Load implements CheckpointStore
+func (s *S3CheckpointStore) Load(partition int32) (int64, bool, error) {
+	checkpoints, err := s.readAll()
+	if err != nil {
+		return 0, false, err
+	}
+	offset, ok := checkpoints[partition]
+	return offset, ok, nil
+}
+
+// Save implements CheckpointStore
+func (s *S3CheckpointStore) Save(partition int32, offset int64) error {
+	checkpoints, err := s.readAll()
+	if err != nil {
+		return err
+	}
+	checkpoints[partition] = offset
+
+	encoded, err := json.Marshal(checkpoints)
+	if err != nil {
+		return err
+	}
+
+	_, err = s.client.PutObject(&s3.PutObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+		Body:   bytes.NewReader(encoded),
+	})
+	return err
+}
+
+func (s *S3CheckpointStore) readAll() (map[int32]int64, error) {
+	out, err := s.client.GetObject(&s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(s.key),
+	})
+	if isNoSuchKeyErr(err) {
+		return make(map[int32]int64), nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read checkpoint object: %v", err)
+	}
+	defer out.Body.Close()
+
+	data, err := ioutil.ReadAll(out.Body)
+	if err != nil {
+		return nil, err
+	}
+
+	checkpoints := make(map[int32]int64)
+	if len(data) == 0 {
+		return checkpoints, nil
+	}
+	if err := json.Unmarshal(data, &checkpoints); err != nil {
+		return nil, err
+	}
+	return checkpoints, nil
+}
+
+func isNoSuchKeyErr(err error) bool {
+	awsErr, ok := err.(awserr.Error)
+	if !ok {
+		return false
+	}
+	return awsErr.Code() == s3.ErrCodeNoSuchKey || awsErr.Code() == "NotFound"
+}
@@ -0,0 +1,276 @@
+package kafka
+
+import (
+	"bytes"
+	"encoding/binary"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// GroupMetadata is the decoded form of a keyver=2 message on the __consumer_offsets topic. Kafka writes one of
+// these every time a consumer group's generation changes (a member joins, leaves, or the group rebalances), and it
+// describes the full membership of the group as of that generation - this is the same information
+// `kafka-consumer-groups.sh --describe` surfaces.
+type GroupMetadata struct {
+	Group        string
+	ProtocolType string
+	Generation   int32
+	Protocol     string
+	Leader       string
+	Members      []GroupMemberMetadata
+}
+
+// GroupMemberMetadata describes a single member of a consumer group as of the generation it was recorded in.
+type GroupMemberMetadata struct {
+	MemberID string
+	// GroupInstanceID is the member's static membership id (KIP-345), set when the member was configured with
+	// group.instance.id. Empty for dynamic members, which is still the common case. Only present at value version 3+.
+	GroupInstanceID  string
+	ClientID         string
+	ClientHost       string
+	SessionTimeout   int32
+	RebalanceTimeout int32
+	// Subscription is the raw, protocol-specific (e.g. ConsumerProtocol) subscription bytes the member sent on join
+	Subscription []byte
+	// Assignment is the raw, protocol-specific partition assignment bytes the group leader computed for this member
+	Assignment []byte
+	// AssignedPartitions is Assignment decoded under the assumption it follows the standard ConsumerProtocol
+	// assignment schema (version int16, then an array of topic -> partitions), keyed by topic name. It is nil if
+	// Assignment could not be decoded as a ConsumerProtocol assignment (e.g. a group using a custom protocol).
+	AssignedPartitions map[string][]int32
+}
+
+// newOffsetGroupMetadata decodes a keyver=2 message into a GroupMetadata. keyBuffer must already have the key
+// version stripped off (see processConsumerOffsetsMessage), leaving just the group name.
+func newOffsetGroupMetadata(keyBuffer *bytes.Buffer, value []byte, logger *log.Entry) (*GroupMetadata, error) {
+	group, err := readString(keyBuffer)
+	if err != nil {
+		logger.Warn("Failed to decode group metadata key", log.Fields{"reason": err.Error()})
+		return nil, err
+	}
+
+	valueBuffer := bytes.NewBuffer(value)
+	var version int16
+	if err := binary.Read(valueBuffer, binary.BigEndian, &version); err != nil {
+		logger.Warn("Failed to decode group metadata value", log.Fields{"reason": "no value version"})
+		return nil, err
+	}
+
+	protocolType, err := readString(valueBuffer)
+	if err != nil {
+		logger.Warn("Failed to decode group metadata value", log.Fields{"reason": "protocol type", "error": err.Error()})
+		return nil, err
+	}
+
+	var generation int32
+	if err := binary.Read(valueBuffer, binary.BigEndian, &generation); err != nil {
+		logger.Warn("Failed to decode group metadata value", log.Fields{"reason": "generation", "error": err.Error()})
+		return nil, err
+	}
+
+	protocol, err := readNullableString(valueBuffer)
+	if err != nil {
+		logger.Warn("Failed to decode group metadata value", log.Fields{"reason": "protocol", "error": err.Error()})
+		return nil, err
+	}
+
+	leader, err := readNullableString(valueBuffer)
+	if err != nil {
+		logger.Warn("Failed to decode group metadata value", log.Fields{"reason": "leader", "error": err.Error()})
+		return nil, err
+	}
+
+	if version >= 2 {
+		// currentStateTimestamp - we have no use for it, skip past it
+		var currentStateTimestamp int64
+		if err := binary.Read(valueBuffer, binary.BigEndian, &currentStateTimestamp); err != nil {
+			logger.Warn("Failed to decode group metadata value", log.Fields{"reason": "current state timestamp", "error": err.Error()})
+			return nil, err
+		}
+	}
+
+	var memberCount int32
+	if err := binary.Read(valueBuffer, binary.BigEndian, &memberCount); err != nil {
+		logger.Warn("Failed to decode group metadata value", log.Fields{"reason": "member count", "error": err.Error()})
+		return nil, err
+	}
+
+	members := make([]GroupMemberMetadata, 0, memberCount)
+	for i := int32(0); i < memberCount; i++ {
+		member, err := readGroupMember(valueBuffer, version)
+		if err != nil {
+			logger.Warn("Failed to decode group metadata member", log.Fields{"reason": err.Error(), "index": i})
+			return nil, err
+		}
+		members = append(members, *member)
+	}
+
+	metadata := &GroupMetadata{
+		Group:        group,
+		ProtocolType: protocolType,
+		Generation:   generation,
+		Protocol:     protocol,
+		Leader:       leader,
+		Members:      members,
+	}
+
+	return metadata, nil
+}
+
+func readGroupMember(buffer *bytes.Buffer, version int16) (*GroupMemberMetadata, error) {
+	memberID, err := readString(buffer)
+	if err != nil {
+		return nil, fmt.Errorf("member_id: %v", err)
+	}
+
+	var groupInstanceID string
+	if version >= 3 {
+		groupInstanceID, err = readNullableString(buffer)
+		if err != nil {
+			return nil, fmt.Errorf("group_instance_id: %v", err)
+		}
+	}
+
+	clientID, err := readString(buffer)
+	if err != nil {
+		return nil, fmt.Errorf("client_id: %v", err)
+	}
+	clientHost, err := readString(buffer)
+	if err != nil {
+		return nil, fmt.Errorf("client_host: %v", err)
+	}
+
+	var rebalanceTimeout int32
+	if version >= 1 {
+		if err := binary.Read(buffer, binary.BigEndian, &rebalanceTimeout); err != nil {
+			return nil, fmt.Errorf("rebalance_timeout: %v", err)
+		}
+	}
+
+	var sessionTimeout int32
+	if err := binary.Read(buffer, binary.BigEndian, &sessionTimeout); err != nil {
+		return nil, fmt.Errorf("session_timeout: %v", err)
+	}
+
+	subscription, err := readBytes(buffer)
+	if err != nil {
+		return nil, fmt.Errorf("subscription: %v", err)
+	}
+	assignment, err := readBytes(buffer)
+	if err != nil {
+		return nil, fmt.Errorf("assignment: %v", err)
+	}
+
+	assignedPartitions, err := decodeConsumerProtocolAssignment(assignment)
+	if err != nil {
+		// A member can use a non-standard assignor whose assignment blob we don't understand. That's not fatal to
+		// decoding the rest of the group metadata, so keep the raw bytes and just leave AssignedPartitions unset.
+		log.WithFields(log.Fields{"member_id": memberID, "error": err.Error()}).Debug("could not decode member assignment as ConsumerProtocol")
+	}
+
+	return &GroupMemberMetadata{
+		MemberID:           memberID,
+		GroupInstanceID:    groupInstanceID,
+		ClientID:           clientID,
+		ClientHost:         clientHost,
+		SessionTimeout:     sessionTimeout,
+		RebalanceTimeout:   rebalanceTimeout,
+		Subscription:       subscription,
+		Assignment:         assignment,
+		AssignedPartitions: assignedPartitions,
+	}, nil
+}
+
+// decodeConsumerProtocolAssignment decodes a member's Assignment bytes under the standard ConsumerProtocol
+// assignment schema: version (int16), followed by an array of (topic string, partitions []int32) entries, followed
+// by a trailing nullable user data byte array we have no use for.
+func decodeConsumerProtocolAssignment(data []byte) (map[string][]int32, error) {
+	if len(data) == 0 {
+		return nil, nil
+	}
+
+	buffer := bytes.NewBuffer(data)
+	var version int16
+	if err := binary.Read(buffer, binary.BigEndian, &version); err != nil {
+		return nil, fmt.Errorf("version: %v", err)
+	}
+
+	var topicCount int32
+	if err := binary.Read(buffer, binary.BigEndian, &topicCount); err != nil {
+		return nil, fmt.Errorf("topic count: %v", err)
+	}
+
+	assignment := make(map[string][]int32, topicCount)
+	for i := int32(0); i < topicCount; i++ {
+		topic, err := readString(buffer)
+		if err != nil {
+			return nil, fmt.Errorf("topic: %v", err)
+		}
+
+		var partitionCount int32
+		if err := binary.Read(buffer, binary.BigEndian, &partitionCount); err != nil {
+			return nil, fmt.Errorf("partition count: %v", err)
+		}
+
+		partitions := make([]int32, partitionCount)
+		for j := range partitions {
+			if err := binary.Read(buffer, binary.BigEndian, &partitions[j]); err != nil {
+				return nil, fmt.Errorf("partition: %v", err)
+			}
+		}
+
+		assignment[topic] = partitions
+	}
+
+	return assignment, nil
+}
+
+// readString reads a Kafka protocol string (int16 length prefix followed by that many bytes).
+func readString(buffer *bytes.Buffer) (string, error) {
+	var length int16
+	if err := binary.Read(buffer, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+	if length < 0 {
+		return "", nil
+	}
+	value := make([]byte, length)
+	if _, err := buffer.Read(value); err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// readNullableString reads a Kafka protocol nullable string (int16 length prefix, -1 meaning "not set" rather than
+// an error, any other non-negative length followed by that many bytes as with readString).
+func readNullableString(buffer *bytes.Buffer) (string, error) {
+	var length int16
+	if err := binary.Read(buffer, binary.BigEndian, &length); err != nil {
+		return "", err
+	}
+	if length < 0 {
+		return "", nil
+	}
+	value := make([]byte, length)
+	if _, err := buffer.Read(value); err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+// readBytes reads a Kafka protocol byte array (int32 length prefix followed by that many bytes).
+func readBytes(buffer *bytes.Buffer) ([]byte, error) {
+	var length int32
+	if err := binary.Read(buffer, binary.BigEndian, &length); err != nil {
+		return nil, err
+	}
+	if length < 0 {
+		return nil, nil
+	}
+	value := make([]byte, length)
+	if _, err := buffer.Read(value); err != nil {
+		return nil, err
+	}
+	return value, nil
+}
@@ -0,0 +1,84 @@
+package kafka
+
+import (
+	"sync"
+
+	"github.com/prometheus/client_golang/prometheus"
+	log "github.com/sirupsen/logrus"
+)
+
+var (
+	groupMemberCountMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_minion_group_member_count",
+		Help: "Number of members currently in the consumer group",
+	}, []string{"group"})
+
+	groupGenerationMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_minion_group_generation",
+		Help: "Current generation id of the consumer group",
+	}, []string{"group"})
+
+	groupMemberAssignedPartitionsMetric = prometheus.NewGaugeVec(prometheus.GaugeOpts{
+		Name: "kafka_minion_group_member_assigned_partitions",
+		Help: "Number of partitions of a topic assigned to a consumer group member",
+	}, []string{"group", "member_id", "client_id", "client_host", "topic"})
+)
+
+func init() {
+	prometheus.MustRegister(groupMemberCountMetric, groupGenerationMetric, groupMemberAssignedPartitionsMetric)
+}
+
+// GroupMetadataCollector keeps the kafka_minion_group_* gauges in sync with the latest generation observed for
+// each consumer group. It is the storage-side counterpart to newOffsetGroupMetadata: that function only decodes a
+// keyver=2 message, this is what turns the decoded result into the metrics `kafka-consumer-groups.sh --describe`
+// would otherwise be needed for.
+type GroupMetadataCollector struct {
+	mu sync.Mutex
+	// assignedPartitionLabels tracks which group_member_assigned_partitions label combinations are currently set
+	// for each group, so that members/topics absent from a new generation have their stale gauge removed instead
+	// of being left behind forever.
+	assignedPartitionLabels map[string][][]string
+}
+
+// NewGroupMetadataCollector creates a GroupMetadataCollector ready to Observe GroupMetadata records.
+func NewGroupMetadataCollector() *GroupMetadataCollector {
+	return &GroupMetadataCollector{
+		assignedPartitionLabels: make(map[string][][]string),
+	}
+}
+
+// Consume reads decoded GroupMetadata off channel, calling Observe for each one, until channel is closed.
+func (c *GroupMetadataCollector) Consume(channel chan *GroupMetadata) {
+	for metadata := range channel {
+		c.Observe(metadata)
+	}
+}
+
+// Observe updates the kafka_minion_group_* gauges for a single decoded GroupMetadata.
+func (c *GroupMetadataCollector) Observe(metadata *GroupMetadata) {
+	groupMemberCountMetric.WithLabelValues(metadata.Group).Set(float64(len(metadata.Members)))
+	groupGenerationMetric.WithLabelValues(metadata.Group).Set(float64(metadata.Generation))
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	for _, labels := range c.assignedPartitionLabels[metadata.Group] {
+		groupMemberAssignedPartitionsMetric.DeleteLabelValues(labels...)
+	}
+
+	newLabels := make([][]string, 0, len(metadata.Members))
+	for _, member := range metadata.Members {
+		for topic, partitions := range member.AssignedPartitions {
+			labels := []string{metadata.Group, member.MemberID, member.ClientID, member.ClientHost, topic}
+			groupMemberAssignedPartitionsMetric.WithLabelValues(labels...).Set(float64(len(partitions)))
+			newLabels = append(newLabels, labels)
+		}
+	}
+	c.assignedPartitionLabels[metadata.Group] = newLabels
+
+	log.WithFields(log.Fields{
+		"group":      metadata.Group,
+		"generation": metadata.Generation,
+		"members":    len(metadata.Members),
+	}).Debug("updated group metadata metrics")
+}
@@ -3,11 +3,28 @@ package kafka
 import (
 	"bytes"
 	"encoding/binary"
+	"fmt"
 	"github.com/Shopify/sarama"
 	"github.com/google-cloud-tools/kafka-minion/options"
 	log "github.com/sirupsen/logrus"
 	"strings"
 	"sync"
+	"time"
+)
+
+const (
+	// defaultPartitionRefreshInterval is used when opts.OffsetsTopicPartitionRefreshIntervalSeconds is not set
+	defaultPartitionRefreshInterval = 5 * time.Minute
+
+	// restartBackoffInitial is the delay before the first retry after a partition consumer fails
+	restartBackoffInitial = 1 * time.Second
+	// restartBackoffMax caps the exponential backoff between partition consumer restart attempts
+	restartBackoffMax = 30 * time.Second
+
+	// defaultCheckpointInterval is used when opts.CheckpointIntervalSeconds is not set
+	defaultCheckpointInterval = 1 * time.Minute
+	// defaultReadyLagThreshold is used when opts.ReadyLagThreshold is not set
+	defaultReadyLagThreshold = int64(1000)
 )
 
 // OffsetConsumer is a consumer module which reads consumer group information from the offsets topic in a Kafka cluster.
@@ -17,20 +34,66 @@ type OffsetConsumer struct {
 	// Waitgroup for all partitionConsumers. For each partition consumer waitgroup is incremented
 	wg sync.WaitGroup
 
-	// QuitChannel is being sent to when a partitionConsumer can not consume messages anymore
+	// quitChannel is closed when the module is shutting down. All partition consumer goroutines, including ones
+	// spawned later by refreshPartitions or restartPartitionConsumer, select on it to stop.
 	quitChannel chan struct{}
 
-	// StorageChannel is used to persist processed messages in memory so that they can be exposed with prometheus
-	storageChannel chan *OffsetEntry
+	// sinks are every destination decoded records are fanned out to, each driven by its own goroutine and queue.
+	// See Sink, SinkConfig and processConsumerOffsetsMessage.
+	sinks []*runningSink
+
+	// partitionConsumersLock guards partitionConsumers
+	partitionConsumersLock sync.Mutex
+	// partitionConsumers tracks the currently running partition consumers by partition id, so that
+	// refreshPartitions can diagnose which partitions are new and which have disappeared since the last refresh.
+	partitionConsumers map[int32]sarama.PartitionConsumer
+	// partitionRefreshInterval controls how often the offsets topic's partition list is re-fetched to discover
+	// partitions added by an admin increasing the topic's partition count.
+	partitionRefreshInterval time.Duration
+
+	// consumer is the sarama.Consumer all partition consumers are created from in standalone mode
+	consumer sarama.Consumer
+
+	// lastOffsetsLock guards lastProcessedOffsets
+	lastOffsetsLock sync.Mutex
+	// lastProcessedOffsets tracks the most recently processed offsets-topic offset per partition. It backs both
+	// checkpointing (checkpointLoop persists it to checkpointStore) and readiness (isPartitionCaughtUp compares it
+	// against the partition's current high-water mark).
+	lastProcessedOffsets map[int32]int64
+
+	// checkpointStore, when non-nil, is used to persist and load per-partition checkpoints so that a restart does
+	// not have to re-read the whole offsets topic from sarama.OffsetOldest. See Start and checkpointLoop.
+	checkpointStore CheckpointStore
+	// checkpointInterval controls how often lastProcessedOffsets is persisted to checkpointStore
+	checkpointInterval time.Duration
+	// readyLagThreshold is how many messages behind a partition's high-water mark is still considered caught up,
+	// see isPartitionCaughtUp
+	readyLagThreshold int64
+
+	// ownedPartitionsLock guards ownedPartitions
+	ownedPartitionsLock sync.RWMutex
+	// ownedPartitions tracks which offsets-topic partitions are currently owned by this instance. When running in
+	// standalone mode this is every partition of the offsets topic. When running in consumer group mode (see
+	// consumerGroupName) this is only the subset of partitions assigned to this instance by the group rebalance, and
+	// processConsumerOffsetsMessage consults it (via IsPartitionOwned) so that a message for a just-revoked partition
+	// in flight through a stale claim goroutine is dropped instead of dispatched.
+	ownedPartitions map[int32]bool
 
 	logger           *log.Entry
 	client           sarama.Client
 	offsetsTopicName string
+
+	// consumerGroupName is the Kafka consumer group Minion itself should join to consume the offsets topic. When
+	// empty (the default) Minion falls back to standalone mode and consumes every partition of the offsets topic
+	// on its own, see Start. When set, multiple Minion instances sharing the same consumerGroupName divide up the
+	// offsets-topic partitions between them and rebalance automatically as instances join or leave, see
+	// StartConsumerGroup and the HA deployment model documented in docs/high-availability.md.
+	consumerGroupName string
 }
 
 // NewOffsetConsumer creates a consumer which process all messages in the __consumer_offsets topic
 // If it cannot connect to the cluster it will panic
-func NewOffsetConsumer(opts *options.Options, storageChannel chan *OffsetEntry) *OffsetConsumer {
+func NewOffsetConsumer(opts *options.Options, sinkConfigs []SinkConfig, checkpointStore CheckpointStore) *OffsetConsumer {
 	logger := log.WithFields(log.Fields{
 		"module": "offset_consumer",
 	})
@@ -49,18 +112,52 @@ func NewOffsetConsumer(opts *options.Options, storageChannel chan *OffsetEntry)
 	}
 	connectionLogger.Info("Successfully connected to kafka cluster")
 
+	refreshInterval := defaultPartitionRefreshInterval
+	if opts.OffsetsTopicPartitionRefreshIntervalSeconds > 0 {
+		refreshInterval = time.Duration(opts.OffsetsTopicPartitionRefreshIntervalSeconds) * time.Second
+	}
+
+	sinks := make([]*runningSink, len(sinkConfigs))
+	for i, cfg := range sinkConfigs {
+		sinks[i] = newRunningSink(fmt.Sprintf("%T", cfg.Sink), cfg)
+	}
+
+	checkpointInterval := defaultCheckpointInterval
+	if opts.CheckpointIntervalSeconds > 0 {
+		checkpointInterval = time.Duration(opts.CheckpointIntervalSeconds) * time.Second
+	}
+	readyLagThreshold := defaultReadyLagThreshold
+	if opts.ReadyLagThreshold > 0 {
+		readyLagThreshold = opts.ReadyLagThreshold
+	}
+
 	return &OffsetConsumer{
-		wg:               sync.WaitGroup{},
-		quitChannel:      make(chan struct{}),
-		storageChannel:   storageChannel,
-		logger:           logger,
-		client:           client,
-		offsetsTopicName: opts.ConsumerOffsetsTopicName,
+		wg:                       sync.WaitGroup{},
+		quitChannel:              make(chan struct{}),
+		sinks:                    sinks,
+		partitionConsumers:       make(map[int32]sarama.PartitionConsumer),
+		partitionRefreshInterval: refreshInterval,
+		lastProcessedOffsets:     make(map[int32]int64),
+		checkpointStore:          checkpointStore,
+		checkpointInterval:       checkpointInterval,
+		readyLagThreshold:        readyLagThreshold,
+		ownedPartitions:          make(map[int32]bool),
+		logger:                   logger,
+		client:                   client,
+		offsetsTopicName:         opts.ConsumerOffsetsTopicName,
+		consumerGroupName:        opts.OffsetsConsumerGroup,
 	}
 }
 
-// Start creates partition consumer for each partition in that topic and starts consuming them
+// Start creates partition consumer for each partition in that topic and starts consuming them. If the Minion instance
+// has been configured with a consumer group name (--offsets-consumer-group) it instead joins that consumer group and
+// lets Kafka's consumer group protocol divide up the offsets-topic partitions between all Minion instances sharing
+// that group name, see StartConsumerGroup.
 func (module *OffsetConsumer) Start() {
+	if module.consumerGroupName != "" {
+		module.StartConsumerGroup()
+		return
+	}
 	defer module.client.Close()
 
 	// Create the consumer from the client
@@ -68,6 +165,7 @@ func (module *OffsetConsumer) Start() {
 	if err != nil {
 		log.Panic("failed to get new consumer", err)
 	}
+	module.consumer = consumer
 
 	// Get the partition count for the offsets topic
 	partitions, err := module.client.Partitions(module.offsetsTopicName)
@@ -78,44 +176,222 @@ func (module *OffsetConsumer) Start() {
 		}).Panic("failed to get partition count")
 	}
 
-	// Default to bootstrapping the offsets topic, unless configured otherwise
-	startFrom := sarama.OffsetOldest
-
 	// Start consumers for each partition with fan in
 	log.WithFields(log.Fields{
 		"topic": module.offsetsTopicName,
 		"count": len(partitions),
 	}).Info("Starting consumers")
-	for i, partition := range partitions {
-		pconsumer, err := consumer.ConsumePartition(module.offsetsTopicName, partition, startFrom)
-		if err != nil {
-			log.WithFields(log.Fields{
-				"topic":     module.offsetsTopicName,
-				"partition": i,
-				"error":     err.Error(),
-			}).Panic("could not start consumer")
-		}
-		module.wg.Add(1)
-		go module.partitionConsumer(pconsumer)
+	for _, partition := range partitions {
+		module.startPartitionConsumer(partition, module.checkpointedStartOffset(partition))
 	}
 	log.WithFields(log.Fields{
 		"topic": module.offsetsTopicName,
 		"count": len(partitions),
 	}).Info("Started all consumers")
+
+	module.wg.Add(1)
+	go module.refreshPartitions()
+
+	module.wg.Add(1)
+	go module.checkpointLoop()
+}
+
+// checkpointedStartOffset returns the offset a partition consumer should start from: one past the last checkpointed
+// offset if a checkpoint store is configured and has one recorded for this partition (the checkpoint records the
+// last offset actually processed, so resuming must start after it), or sarama.OffsetOldest (bootstrap the whole
+// compacted topic) otherwise.
+func (module *OffsetConsumer) checkpointedStartOffset(partition int32) int64 {
+	if module.checkpointStore == nil {
+		return sarama.OffsetOldest
+	}
+
+	offset, ok, err := module.checkpointStore.Load(partition)
+	if err != nil {
+		module.logger.WithFields(log.Fields{
+			"partition": partition,
+			"error":     err.Error(),
+		}).Warn("failed to load checkpoint, bootstrapping partition from oldest")
+		return sarama.OffsetOldest
+	}
+	if !ok {
+		return sarama.OffsetOldest
+	}
+
+	// Seed lastProcessedOffsets with the checkpoint so readiness and restarts account for the resumed position even
+	// before the partition's first new message arrives.
+	module.setLastProcessedOffset(partition, offset)
+	return offset + 1
+}
+
+// startPartitionConsumer creates a partition consumer for the given partition starting from startFrom. If the
+// broker cannot serve the request right now (e.g. a leader election is in progress) it no longer panics; instead it
+// retries in the background with an exponential backoff via restartPartitionConsumer.
+func (module *OffsetConsumer) startPartitionConsumer(partition int32, startFrom int64) {
+	pconsumer, err := module.consumer.ConsumePartition(module.offsetsTopicName, partition, startFrom)
+	if err != nil {
+		module.logger.WithFields(log.Fields{
+			"topic":     module.offsetsTopicName,
+			"partition": partition,
+			"error":     err.Error(),
+		}).Error("could not start partition consumer, retrying with backoff")
+		go module.restartPartitionConsumer(partition, startFrom, restartBackoffInitial)
+		return
+	}
+
+	module.partitionConsumersLock.Lock()
+	module.partitionConsumers[partition] = pconsumer
+	module.partitionConsumersLock.Unlock()
+	module.setPartitionOwned(partition, true)
+
+	module.wg.Add(1)
+	go module.partitionConsumer(partition, pconsumer)
+}
+
+// restartPartitionConsumer waits out backoff, then retries startPartitionConsumer, doubling backoff (capped at
+// restartBackoffMax) on repeated failure. It gives up only when the module is shutting down.
+func (module *OffsetConsumer) restartPartitionConsumer(partition int32, startFrom int64, backoff time.Duration) {
+	select {
+	case <-module.quitChannel:
+		return
+	case <-time.After(backoff):
+	}
+
+	pconsumer, err := module.consumer.ConsumePartition(module.offsetsTopicName, partition, startFrom)
+	if err != nil {
+		next := backoff * 2
+		if next > restartBackoffMax {
+			next = restartBackoffMax
+		}
+		module.logger.WithFields(log.Fields{
+			"topic":     module.offsetsTopicName,
+			"partition": partition,
+			"error":     err.Error(),
+			"retry_in":  next,
+		}).Error("partition consumer restart failed, backing off")
+		go module.restartPartitionConsumer(partition, startFrom, next)
+		return
+	}
+
+	module.partitionConsumersLock.Lock()
+	module.partitionConsumers[partition] = pconsumer
+	module.partitionConsumersLock.Unlock()
+	module.setPartitionOwned(partition, true)
+
+	module.wg.Add(1)
+	go module.partitionConsumer(partition, pconsumer)
+}
+
+// refreshPartitions periodically re-lists the offsets topic's partitions so that partitions added after startup
+// (e.g. an admin increased the topic's partition count) are picked up without a restart, and removed partitions
+// have their consumers closed.
+func (module *OffsetConsumer) refreshPartitions() {
+	defer module.wg.Done()
+
+	ticker := time.NewTicker(module.partitionRefreshInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			module.syncPartitions()
+		case <-module.quitChannel:
+			return
+		}
+	}
+}
+
+// syncPartitions diffs the offsets topic's current partition list against the partition consumers we already have
+// running, starting consumers for newly discovered partitions and closing ones whose partitions have disappeared.
+func (module *OffsetConsumer) syncPartitions() {
+	// client.Partitions returns sarama's cached metadata, which only picks up newly added partitions once sarama
+	// happens to refresh it on its own; force a refresh here so discovery actually happens every tick.
+	if err := module.client.RefreshMetadata(module.offsetsTopicName); err != nil {
+		module.logger.WithFields(log.Fields{
+			"topic": module.offsetsTopicName,
+			"error": err.Error(),
+		}).Warn("failed to refresh offsets topic metadata, partition list may be stale")
+	}
+
+	partitions, err := module.client.Partitions(module.offsetsTopicName)
+	if err != nil {
+		module.logger.WithFields(log.Fields{
+			"topic": module.offsetsTopicName,
+			"error": err.Error(),
+		}).Error("failed to refresh offsets topic partition list, keeping current consumers")
+		return
+	}
+
+	current := make(map[int32]bool, len(partitions))
+	for _, partition := range partitions {
+		current[partition] = true
+
+		module.partitionConsumersLock.Lock()
+		_, exists := module.partitionConsumers[partition]
+		module.partitionConsumersLock.Unlock()
+		if !exists {
+			module.logger.WithFields(log.Fields{"partition": partition}).Info("discovered new offsets topic partition")
+			module.startPartitionConsumer(partition, sarama.OffsetOldest)
+		}
+	}
+
+	module.partitionConsumersLock.Lock()
+	removed := make([]int32, 0)
+	for partition, pconsumer := range module.partitionConsumers {
+		if !current[partition] {
+			// pconsumer is nil while a restart is pending (see partitionConsumer's error path); nothing to close yet.
+			if pconsumer != nil {
+				pconsumer.AsyncClose()
+			}
+			delete(module.partitionConsumers, partition)
+			removed = append(removed, partition)
+		}
+	}
+	module.partitionConsumersLock.Unlock()
+
+	for _, partition := range removed {
+		module.logger.WithFields(log.Fields{"partition": partition}).Info("offsets topic partition disappeared, closed consumer")
+		module.setPartitionOwned(partition, false)
+	}
 }
 
-// partitionConsumer is a worker routine which consumes a single partition in the __consumer_offsets topic
-func (module *OffsetConsumer) partitionConsumer(consumer sarama.PartitionConsumer) {
+// partitionConsumer is a worker routine which consumes a single partition in the __consumer_offsets topic. On a
+// consume error it closes the failed partition consumer and schedules a restart (via restartPartitionConsumer)
+// resuming from the last successfully processed offset, rather than taking down the whole pod.
+func (module *OffsetConsumer) partitionConsumer(partition int32, consumer sarama.PartitionConsumer) {
 	defer module.wg.Done()
-	defer consumer.AsyncClose()
 
 	for {
 		select {
-		case msg := <-consumer.Messages():
+		case msg, ok := <-consumer.Messages():
+			if !ok {
+				return
+			}
 			module.processConsumerOffsetsMessage(msg)
-		case err := <-consumer.Errors():
-			log.Errorf("consume error. %+v %+v %+v", err.Topic, err.Partition, err.Err.Error())
+		case err, ok := <-consumer.Errors():
+			if !ok {
+				return
+			}
+			module.logger.WithFields(log.Fields{
+				"topic":     err.Topic,
+				"partition": err.Partition,
+				"error":     err.Err.Error(),
+			}).Error("partition consumer error, restarting")
+			consumer.AsyncClose()
+
+			// Leave a nil placeholder rather than deleting the key: the partition is still "ours", just pending
+			// restart, so syncPartitions must not see it as missing and start a second consumer for it from oldest.
+			module.partitionConsumersLock.Lock()
+			module.partitionConsumers[partition] = nil
+			module.partitionConsumersLock.Unlock()
+
+			startFrom := sarama.OffsetOldest
+			if lastOffset, ok := module.getLastProcessedOffset(partition); ok {
+				startFrom = lastOffset + 1
+			}
+			go module.restartPartitionConsumer(partition, startFrom, restartBackoffInitial)
+			return
 		case <-module.quitChannel:
+			consumer.AsyncClose()
 			return
 		}
 	}
@@ -123,10 +399,22 @@ func (module *OffsetConsumer) partitionConsumer(consumer sarama.PartitionConsume
 
 // processConsumerOffsetsMessage is responsible for decoding the consumer offsets message
 func (module *OffsetConsumer) processConsumerOffsetsMessage(msg *sarama.ConsumerMessage) {
+	defer module.setLastProcessedOffset(msg.Partition, msg.Offset)
+
 	logger := log.WithFields(log.Fields{"offset_topic": msg.Topic, "offset_partition": msg.Partition, "offset_offset": msg.Offset})
 
+	if !module.IsPartitionOwned(msg.Partition) {
+		// Can happen in consumer group mode: a message for a partition that has just been revoked (Cleanup already
+		// ran) may still be in flight through this partition's claim goroutine. Drop it rather than dispatching
+		// state for a partition this instance no longer owns.
+		logger.Debug("dropped message for partition we no longer own")
+		return
+	}
+
 	if len(msg.Value) == 0 {
-		// Tombstone message - we don't handle them for now
+		// Tombstone message - we don't handle them for now. For a keyver=2 tombstone (a group's final metadata
+		// record being removed by compaction) this means kafka_minion_group_member_count/_generation never get
+		// cleared for a deleted group, same as the pre-existing offset-entry metrics in this situation.
 		logger.Debug("dropped tombstone")
 		return
 	}
@@ -145,14 +433,26 @@ func (module *OffsetConsumer) processConsumerOffsetsMessage(msg *sarama.Consumer
 		if err != nil {
 			break
 		}
-		module.storageChannel <- offset
+		module.dispatchToSinks(sinkMessage{entry: offset})
 	case 2:
-		processGroupMetadata(keyBuffer, msg.Value, logger)
+		metadata, err := processGroupMetadata(keyBuffer, msg.Value, logger)
+		if err != nil {
+			break
+		}
+		module.dispatchToSinks(sinkMessage{metadata: metadata})
 	default:
 		logger.Warn("Failed to decode offset message", log.Fields{"reason": "unknown key version", "version": keyver})
 	}
 }
 
+// dispatchToSinks fans a decoded message out to every configured sink. Each sink is fed through its own bounded
+// queue (see SinkConfig), so one slow sink cannot stall the others or the decode path itself.
+func (module *OffsetConsumer) dispatchToSinks(msg sinkMessage) {
+	for _, sink := range module.sinks {
+		sink.dispatch(msg, module.quitChannel)
+	}
+}
+
 func processKeyAndOffset(buffer *bytes.Buffer, value []byte, logger *log.Entry) (*OffsetEntry, error) {
 	offset, err := newOffsetEntry(buffer, value, logger)
 	if err != nil {
@@ -163,7 +463,154 @@ func processKeyAndOffset(buffer *bytes.Buffer, value []byte, logger *log.Entry)
 	return offset, nil
 }
 
-func processGroupMetadata(keyBuffer *bytes.Buffer, value []byte, logger *log.Entry) {
+func processGroupMetadata(keyBuffer *bytes.Buffer, value []byte, logger *log.Entry) (*GroupMetadata, error) {
 	// Group metadata contains client information (such as owner's IP address), how many partitions are assigned to a group member etc
-	newOffsetGroupMetadata(keyBuffer, value, logger)
+	metadata, err := newOffsetGroupMetadata(keyBuffer, value, logger)
+	if err != nil {
+		return nil, err
+	}
+	logger.Debugf("Group %v - Generation: %v - Members: %v", metadata.Group, metadata.Generation, len(metadata.Members))
+
+	return metadata, nil
+}
+
+// setPartitionOwned updates which offsets-topic partitions this instance currently owns.
+// processConsumerOffsetsMessage consults IsPartitionOwned before dispatching a decoded message so that, when several
+// Minion instances share the offsets topic via a consumer group (see StartConsumerGroup), each instance's
+// Prometheus output only reflects the partitions it was actually assigned. Metrics already recorded from a
+// partition are not removed when it stops being owned.
+func (module *OffsetConsumer) setPartitionOwned(partition int32, owned bool) {
+	module.ownedPartitionsLock.Lock()
+	defer module.ownedPartitionsLock.Unlock()
+
+	if owned {
+		module.ownedPartitions[partition] = true
+	} else {
+		delete(module.ownedPartitions, partition)
+	}
+}
+
+// IsPartitionOwned returns whether the given offsets-topic partition is currently owned by this instance.
+func (module *OffsetConsumer) IsPartitionOwned(partition int32) bool {
+	module.ownedPartitionsLock.RLock()
+	defer module.ownedPartitionsLock.RUnlock()
+
+	return module.ownedPartitions[partition]
+}
+
+// Stop signals every partition consumer goroutine to exit, waits for them to finish, and then closes every
+// configured sink. It is only safe to call once.
+func (module *OffsetConsumer) Stop() {
+	close(module.quitChannel)
+	module.wg.Wait()
+	for _, sink := range module.sinks {
+		sink.close()
+	}
+}
+
+// setLastProcessedOffset records offset as the most recently processed offset for partition.
+func (module *OffsetConsumer) setLastProcessedOffset(partition int32, offset int64) {
+	module.lastOffsetsLock.Lock()
+	defer module.lastOffsetsLock.Unlock()
+
+	module.lastProcessedOffsets[partition] = offset
+}
+
+// getLastProcessedOffset returns the most recently processed offset for partition, and ok=false if none has been
+// processed yet (e.g. the partition consumer failed before its first message).
+func (module *OffsetConsumer) getLastProcessedOffset(partition int32) (int64, bool) {
+	module.lastOffsetsLock.Lock()
+	defer module.lastOffsetsLock.Unlock()
+
+	offset, ok := module.lastProcessedOffsets[partition]
+	return offset, ok
+}
+
+// checkpointLoop periodically persists the last processed offset of every partition to checkpointStore, so a
+// restart can resume close to where this instance left off instead of bootstrapping the whole offsets topic again.
+// It is a no-op (beyond waiting for shutdown) when no checkpointStore is configured.
+func (module *OffsetConsumer) checkpointLoop() {
+	defer module.wg.Done()
+
+	if module.checkpointStore == nil {
+		<-module.quitChannel
+		return
+	}
+
+	ticker := time.NewTicker(module.checkpointInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ticker.C:
+			module.writeCheckpoints()
+		case <-module.quitChannel:
+			module.writeCheckpoints()
+			return
+		}
+	}
+}
+
+// writeCheckpoints saves the current lastProcessedOffsets snapshot to checkpointStore, one partition at a time.
+func (module *OffsetConsumer) writeCheckpoints() {
+	module.lastOffsetsLock.Lock()
+	offsets := make(map[int32]int64, len(module.lastProcessedOffsets))
+	for partition, offset := range module.lastProcessedOffsets {
+		offsets[partition] = offset
+	}
+	module.lastOffsetsLock.Unlock()
+
+	for partition, offset := range offsets {
+		if err := module.checkpointStore.Save(partition, offset); err != nil {
+			module.logger.WithFields(log.Fields{
+				"partition": partition,
+				"error":     err.Error(),
+			}).Error("failed to save offsets topic checkpoint")
+		}
+	}
+}
+
+// IsReady reports whether every offsets-topic partition this instance currently owns has caught up to within
+// readyLagThreshold messages of its high-water mark. It is meant to back a /healthz readiness probe so that
+// requests 503 until Minion has (re-)bootstrapped from the offsets topic, since metrics reported before that point
+// are necessarily incomplete.
+func (module *OffsetConsumer) IsReady() bool {
+	module.ownedPartitionsLock.RLock()
+	owned := make([]int32, 0, len(module.ownedPartitions))
+	for partition := range module.ownedPartitions {
+		owned = append(owned, partition)
+	}
+	module.ownedPartitionsLock.RUnlock()
+
+	if len(owned) == 0 {
+		return false
+	}
+
+	for _, partition := range owned {
+		if !module.isPartitionCaughtUp(partition) {
+			return false
+		}
+	}
+	return true
+}
+
+// isPartitionCaughtUp compares the last offset we've processed for partition against its current high-water mark.
+func (module *OffsetConsumer) isPartitionCaughtUp(partition int32) bool {
+	highWaterMark, err := module.client.GetOffset(module.offsetsTopicName, partition, sarama.OffsetNewest)
+	if err != nil {
+		module.logger.WithFields(log.Fields{
+			"partition": partition,
+			"error":     err.Error(),
+		}).Warn("failed to get high water mark for readiness check")
+		return false
+	}
+
+	lastOffset, seen := module.getLastProcessedOffset(partition)
+	if !seen {
+		// An empty partition (high-water mark 0) has nothing to catch up on
+		return highWaterMark <= 0
+	}
+
+	// highWaterMark is the offset of the next message to be written, so lastOffset+1 means nothing is left to read
+	return highWaterMark-(lastOffset+1) <= module.readyLagThreshold
 }
@@ -0,0 +1,101 @@
+package kafka
+
+import (
+	"context"
+
+	"github.com/Shopify/sarama"
+	log "github.com/sirupsen/logrus"
+)
+
+// StartConsumerGroup joins the configured consumer group (consumerGroupName) and lets Kafka's consumer group
+// protocol assign this instance a subset of the offsets-topic partitions. Unlike standalone mode (see Start), this
+// allows several Minion instances to share the load of reading the (potentially very large) __consumer_offsets
+// topic, each holding only the partitions it owns in memory. See docs/high-availability.md for the deployment model.
+func (module *OffsetConsumer) StartConsumerGroup() {
+	defer module.client.Close()
+
+	group, err := sarama.NewConsumerGroupFromClient(module.consumerGroupName, module.client)
+	if err != nil {
+		module.logger.WithFields(log.Fields{
+			"group": module.consumerGroupName,
+			"error": err.Error(),
+		}).Panic("failed to create consumer group")
+	}
+
+	handler := &offsetConsumerGroupHandler{module: module}
+	ctx, cancel := context.WithCancel(context.Background())
+
+	module.wg.Add(1)
+	go func() {
+		defer module.wg.Done()
+		for {
+			// Consume blocks until a rebalance happens, in which case ConsumeClaim returns and we call Consume
+			// again so the handler picks up its (possibly changed) partition assignment.
+			if err := group.Consume(ctx, []string{module.offsetsTopicName}, handler); err != nil {
+				module.logger.WithFields(log.Fields{
+					"group": module.consumerGroupName,
+					"error": err.Error(),
+				}).Error("consumer group session ended with error")
+			}
+			if ctx.Err() != nil {
+				return
+			}
+		}
+	}()
+
+	module.logger.WithFields(log.Fields{
+		"topic": module.offsetsTopicName,
+		"group": module.consumerGroupName,
+	}).Info("joined consumer group for offsets topic")
+
+	go func() {
+		for err := range group.Errors() {
+			module.logger.WithFields(log.Fields{
+				"group": module.consumerGroupName,
+				"error": err.Error(),
+			}).Error("consumer group error")
+		}
+	}()
+
+	<-module.quitChannel
+	cancel()
+	group.Close()
+}
+
+// offsetConsumerGroupHandler implements sarama.ConsumerGroupHandler and feeds claimed messages through the same
+// decoding path standalone mode uses (processConsumerOffsetsMessage), so the offsets topic is processed identically
+// regardless of which mode Minion is running in.
+type offsetConsumerGroupHandler struct {
+	module *OffsetConsumer
+}
+
+// Setup is run at the beginning of a new consumer group session, once the partition assignment for this instance
+// is known. We record the assigned partitions so processConsumerOffsetsMessage only dispatches messages from
+// partitions this instance owns.
+func (h *offsetConsumerGroupHandler) Setup(session sarama.ConsumerGroupSession) error {
+	for _, partition := range session.Claims()[h.module.offsetsTopicName] {
+		h.module.setPartitionOwned(partition, true)
+	}
+	h.module.logger.WithFields(log.Fields{
+		"partitions": session.Claims()[h.module.offsetsTopicName],
+	}).Info("consumer group assigned partitions")
+	return nil
+}
+
+// Cleanup is run at the end of a consumer group session, i.e. before a rebalance takes our partitions away again.
+func (h *offsetConsumerGroupHandler) Cleanup(session sarama.ConsumerGroupSession) error {
+	for _, partition := range session.Claims()[h.module.offsetsTopicName] {
+		h.module.setPartitionOwned(partition, false)
+	}
+	return nil
+}
+
+// ConsumeClaim processes messages for a single partition claim. It returns once the partition is revoked, which
+// happens on rebalance or when the session's context is cancelled.
+func (h *offsetConsumerGroupHandler) ConsumeClaim(session sarama.ConsumerGroupSession, claim sarama.ConsumerGroupClaim) error {
+	for msg := range claim.Messages() {
+		h.module.processConsumerOffsetsMessage(msg)
+		session.MarkMessage(msg, "")
+	}
+	return nil
+}
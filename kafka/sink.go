@@ -0,0 +1,98 @@
+package kafka
+
+import (
+	log "github.com/sirupsen/logrus"
+)
+
+// Sink is the interface every destination for decoded __consumer_offsets records must implement. OffsetConsumer
+// fans every decoded OffsetEntry and GroupMetadata out to all sinks configured via NewOffsetConsumer, so adding a
+// new destination only means implementing this interface and constructing it from options - the decode path in
+// processConsumerOffsetsMessage never needs to change.
+type Sink interface {
+	// Write is called once for every decoded offset commit (keyver 0 or 1) message
+	Write(entry *OffsetEntry) error
+	// WriteGroupMetadata is called once for every decoded group metadata (keyver 2) message
+	WriteGroupMetadata(metadata *GroupMetadata) error
+	// Close releases any resources held by the sink (connections, file handles, ...)
+	Close()
+}
+
+// SinkConfig bundles a Sink with how OffsetConsumer should feed it.
+type SinkConfig struct {
+	Sink Sink
+	// BufferSize is how many pending messages the sink's queue can hold before DropOnFull decides what happens.
+	BufferSize int
+	// DropOnFull, when true, discards new messages once the sink's queue is full instead of blocking the decode
+	// path. Use this for best-effort sinks (e.g. a debugging stdout sink); leave false for sinks that must not
+	// lose data, at the cost of backpressuring message decoding if that sink falls behind.
+	DropOnFull bool
+}
+
+// sinkMessage is the unit of work queued for a sink; exactly one of entry/metadata is set.
+type sinkMessage struct {
+	entry    *OffsetEntry
+	metadata *GroupMetadata
+}
+
+// runningSink drives a single Sink from its own goroutine and bounded queue, so that a slow or stuck sink cannot
+// stall decoding of the offsets topic for every other sink.
+type runningSink struct {
+	sink       Sink
+	queue      chan sinkMessage
+	dropOnFull bool
+	logger     *log.Entry
+}
+
+func newRunningSink(name string, cfg SinkConfig) *runningSink {
+	bufferSize := cfg.BufferSize
+	if bufferSize <= 0 {
+		bufferSize = 100
+	}
+
+	rs := &runningSink{
+		sink:       cfg.Sink,
+		queue:      make(chan sinkMessage, bufferSize),
+		dropOnFull: cfg.DropOnFull,
+		logger:     log.WithFields(log.Fields{"module": "sink", "sink": name}),
+	}
+	go rs.run()
+
+	return rs
+}
+
+func (rs *runningSink) run() {
+	for msg := range rs.queue {
+		if msg.entry != nil {
+			if err := rs.sink.Write(msg.entry); err != nil {
+				rs.logger.WithFields(log.Fields{"error": err.Error()}).Error("failed to write offset entry")
+			}
+			continue
+		}
+		if err := rs.sink.WriteGroupMetadata(msg.metadata); err != nil {
+			rs.logger.WithFields(log.Fields{"error": err.Error()}).Error("failed to write group metadata")
+		}
+	}
+	rs.sink.Close()
+}
+
+// dispatch queues msg for the sink. A non-drop sink still blocks the caller while its queue is full, but also
+// selects on quit so a stuck sink cannot stall the partition consumer goroutine past shutdown and deadlock Stop's
+// wg.Wait().
+func (rs *runningSink) dispatch(msg sinkMessage, quit <-chan struct{}) {
+	if rs.dropOnFull {
+		select {
+		case rs.queue <- msg:
+		default:
+			rs.logger.Warn("sink queue full, dropping message")
+		}
+		return
+	}
+	select {
+	case rs.queue <- msg:
+	case <-quit:
+	}
+}
+
+func (rs *runningSink) close() {
+	close(rs.queue)
+}
@@ -0,0 +1,64 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+
+	"github.com/Shopify/sarama"
+	log "github.com/sirupsen/logrus"
+)
+
+// KafkaSink re-publishes decoded offsets-topic records, JSON-encoded, onto a separate output topic. This lets
+// downstream systems subscribe to offset and group metadata changes without having to reimplement the binary
+// __consumer_offsets decoding themselves.
+type KafkaSink struct {
+	producer sarama.SyncProducer
+	topic    string
+	logger   *log.Entry
+}
+
+// NewKafkaSink creates a Sink which publishes JSON-encoded records to outputTopic. config should have
+// Producer.Return.Successes enabled, as is required for a sarama.SyncProducer.
+func NewKafkaSink(brokers []string, config *sarama.Config, outputTopic string) (*KafkaSink, error) {
+	producer, err := sarama.NewSyncProducer(brokers, config)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create kafka sink producer: %v", err)
+	}
+
+	return &KafkaSink{
+		producer: producer,
+		topic:    outputTopic,
+		logger:   log.WithFields(log.Fields{"module": "kafka_sink", "topic": outputTopic}),
+	}, nil
+}
+
+// Write implements Sink
+func (s *KafkaSink) Write(entry *OffsetEntry) error {
+	return s.publish(entry.Group, entry)
+}
+
+// WriteGroupMetadata implements Sink
+func (s *KafkaSink) WriteGroupMetadata(metadata *GroupMetadata) error {
+	return s.publish(metadata.Group, metadata)
+}
+
+func (s *KafkaSink) publish(key string, v interface{}) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+
+	_, _, err = s.producer.SendMessage(&sarama.ProducerMessage{
+		Topic: s.topic,
+		Key:   sarama.StringEncoder(key),
+		Value: sarama.ByteEncoder(encoded),
+	})
+	return err
+}
+
+// Close implements Sink
+func (s *KafkaSink) Close() {
+	if err := s.producer.Close(); err != nil {
+		s.logger.WithFields(log.Fields{"error": err.Error()}).Error("failed to close kafka sink producer")
+	}
+}
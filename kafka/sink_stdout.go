@@ -0,0 +1,42 @@
+package kafka
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+)
+
+// StdoutSink writes every decoded record as a single line of JSON to os.Stdout (see NewStdoutSink). It
+// is meant for debugging a running Minion instance or piping decoded records into another unix tool, not for
+// production use - it never buffers or retries.
+type StdoutSink struct {
+	out io.Writer
+}
+
+// NewStdoutSink creates a Sink that writes JSON lines to os.Stdout.
+func NewStdoutSink() *StdoutSink {
+	return &StdoutSink{out: os.Stdout}
+}
+
+// Write implements Sink
+func (s *StdoutSink) Write(entry *OffsetEntry) error {
+	return s.writeLine(entry)
+}
+
+// WriteGroupMetadata implements Sink
+func (s *StdoutSink) WriteGroupMetadata(metadata *GroupMetadata) error {
+	return s.writeLine(metadata)
+}
+
+func (s *StdoutSink) writeLine(v interface{}) error {
+	encoded, err := json.Marshal(v)
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintln(s.out, string(encoded))
+	return err
+}
+
+// Close implements Sink
+func (s *StdoutSink) Close() {}
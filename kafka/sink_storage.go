@@ -0,0 +1,34 @@
+package kafka
+
+// StorageSink forwards decoded offset entries onto the in-memory storage channel the Prometheus exporter reads
+// from, and decoded group metadata into a GroupMetadataCollector which maintains the kafka_minion_group_* gauges
+// directly. This is the sink that backed OffsetConsumer before sinks became pluggable, and remains the default one.
+type StorageSink struct {
+	storageChannel         chan *OffsetEntry
+	groupMetadataCollector *GroupMetadataCollector
+}
+
+// NewStorageSink creates a Sink which writes offset entries onto storageChannel and feeds group metadata into
+// collector.
+func NewStorageSink(storageChannel chan *OffsetEntry, collector *GroupMetadataCollector) *StorageSink {
+	return &StorageSink{
+		storageChannel:         storageChannel,
+		groupMetadataCollector: collector,
+	}
+}
+
+// Write implements Sink
+func (s *StorageSink) Write(entry *OffsetEntry) error {
+	s.storageChannel <- entry
+	return nil
+}
+
+// WriteGroupMetadata implements Sink
+func (s *StorageSink) WriteGroupMetadata(metadata *GroupMetadata) error {
+	s.groupMetadataCollector.Observe(metadata)
+	return nil
+}
+
+// Close implements Sink. The storage channel and collector are owned by the caller, so there is nothing to
+// release here.
+func (s *StorageSink) Close() {}
@@ -0,0 +1,86 @@
+package options
+
+import (
+	"flag"
+	"strings"
+)
+
+// Options holds every setting Minion's Kafka-facing components need, populated from command line flags in
+// NewOptions. Environment variables are not read.
+type Options struct {
+	// KafkaBrokers is the list of bootstrap brokers to connect to.
+	KafkaBrokers []string
+
+	// ConsumerOffsetsTopicName is the name of the topic Minion decodes consumer group offsets and metadata from.
+	// This is __consumer_offsets on every real cluster; it's only configurable to make testing against a
+	// differently-named topic possible.
+	ConsumerOffsetsTopicName string
+
+	// OffsetsConsumerGroup, when set, switches Minion into consumer group mode: instead of consuming every
+	// partition of the offsets topic directly, this instance joins the named consumer group and only processes the
+	// partitions Kafka's group protocol assigns it, sharing the load with any other Minion instance using the same
+	// group name. See docs/high-availability.md.
+	OffsetsConsumerGroup string
+
+	// OffsetsTopicPartitionRefreshIntervalSeconds controls how often the offsets topic's partition list is
+	// re-checked for partitions added after startup. 0 means use the package default.
+	OffsetsTopicPartitionRefreshIntervalSeconds int
+
+	// CheckpointIntervalSeconds controls how often processed offsets-topic offsets are persisted to the configured
+	// checkpoint store. 0 means use the package default.
+	CheckpointIntervalSeconds int
+
+	// ReadyLagThreshold is how many messages a partition may lag behind its high-water mark and still be considered
+	// caught up for readiness purposes. 0 means use the package default.
+	ReadyLagThreshold int64
+
+	// StdoutSinkEnabled, when true, adds a StdoutSink so decoded records are also written as JSON lines to stdout.
+	// Meant for debugging a running instance, not production use - see StdoutSink.
+	StdoutSinkEnabled bool
+
+	// KafkaSinkOutputTopic, when non-empty, adds a KafkaSink which republishes every decoded record as JSON onto
+	// this topic, so downstream systems can subscribe without reimplementing the offsets topic decoding themselves.
+	KafkaSinkOutputTopic string
+
+	// CheckpointBackend selects which CheckpointStore implementation NewOffsetConsumer is given: "none" (the
+	// default - no checkpointing, every restart re-reads the offsets topic from oldest), "file", "kafka", or "s3".
+	// See FileCheckpointStore, KafkaCheckpointStore and S3CheckpointStore respectively.
+	CheckpointBackend string
+
+	// CheckpointFilePath is the JSON file path used when CheckpointBackend is "file".
+	CheckpointFilePath string
+
+	// CheckpointKafkaTopic is the compacted, single-partition topic used when CheckpointBackend is "kafka".
+	CheckpointKafkaTopic string
+
+	// CheckpointS3Bucket and CheckpointS3Key locate the checkpoint object used when CheckpointBackend is "s3".
+	CheckpointS3Bucket string
+	CheckpointS3Key    string
+}
+
+// NewOptions parses command line flags into an Options. On invalid flag values flag.Parse prints the usage and
+// exits the process, matching the fail-fast startup style the rest of Minion uses for unrecoverable configuration
+// problems.
+func NewOptions() *Options {
+	opts := &Options{}
+
+	var brokers string
+	flag.StringVar(&brokers, "kafka-brokers", "", "Comma separated list of Kafka brokers to connect to")
+	flag.StringVar(&opts.ConsumerOffsetsTopicName, "offsets-topic-name", "__consumer_offsets", "Name of the Kafka offsets topic to consume")
+	flag.StringVar(&opts.OffsetsConsumerGroup, "offsets-consumer-group", "", "If set, consume the offsets topic via this consumer group instead of standalone mode, so multiple Minion instances can share the load")
+	flag.IntVar(&opts.OffsetsTopicPartitionRefreshIntervalSeconds, "offsets-topic-partition-refresh-interval-seconds", 0, "How often to check the offsets topic for newly added partitions (0 = package default)")
+	flag.IntVar(&opts.CheckpointIntervalSeconds, "checkpoint-interval-seconds", 0, "How often to persist offsets topic checkpoints (0 = package default)")
+	flag.Int64Var(&opts.ReadyLagThreshold, "ready-lag-threshold", 0, "Maximum offsets topic lag behind the high-water mark still considered caught up (0 = package default)")
+	flag.BoolVar(&opts.StdoutSinkEnabled, "stdout-sink-enabled", false, "Write decoded records as JSON lines to stdout, in addition to any other configured sinks")
+	flag.StringVar(&opts.KafkaSinkOutputTopic, "kafka-sink-output-topic", "", "If set, republish decoded records as JSON onto this Kafka topic")
+	flag.StringVar(&opts.CheckpointBackend, "checkpoint-backend", "none", "Checkpoint store to use: none, file, kafka, or s3")
+	flag.StringVar(&opts.CheckpointFilePath, "checkpoint-file-path", "", "JSON file path for the file checkpoint backend")
+	flag.StringVar(&opts.CheckpointKafkaTopic, "checkpoint-kafka-topic", "", "Compacted topic for the kafka checkpoint backend")
+	flag.StringVar(&opts.CheckpointS3Bucket, "checkpoint-s3-bucket", "", "Bucket for the s3 checkpoint backend")
+	flag.StringVar(&opts.CheckpointS3Key, "checkpoint-s3-key", "", "Object key for the s3 checkpoint backend")
+	flag.Parse()
+
+	opts.KafkaBrokers = strings.Split(brokers, ",")
+
+	return opts
+}